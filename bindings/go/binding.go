@@ -7,9 +7,52 @@ package tree_sitter_typescript
 // #endif
 import "C"
 
-import "unsafe"
+import (
+	_ "embed"
+	"fmt"
+	"unsafe"
+)
+
+//go:generate cp ../../src/node-types.json node-types.json
+
+//go:embed node-types.json
+var nodeTypes []byte
 
 // Get the tree-sitter Language for this grammar.
 func Language() unsafe.Pointer {
 	return unsafe.Pointer(C.tree_sitter_typescript())
 }
+
+// Version information recorded in the generated parser for this grammar.
+type LanguageMetadata struct {
+	// The tree-sitter language ABI the parser was generated for.
+	ABIVersion uint32
+	// The grammar version, formatted as major.minor.patch.
+	GrammarVersion string
+}
+
+// Get the version information recorded in the generated parser.
+func Metadata() LanguageMetadata {
+	language := C.tree_sitter_typescript()
+	return LanguageMetadata{
+		ABIVersion: uint32(language.abi_version),
+		GrammarVersion: fmt.Sprintf(
+			"%d.%d.%d",
+			language.metadata.major_version,
+			language.metadata.minor_version,
+			language.metadata.patch_version,
+		),
+	}
+}
+
+// Get the contents of node-types.json for this grammar.
+func NodeTypes() []byte {
+	return nodeTypes
+}
+
+// Get the tree-sitter Languages provided by this package, keyed by name.
+func Languages() map[string]unsafe.Pointer {
+	return map[string]unsafe.Pointer{
+		"typescript": Language(),
+	}
+}
@@ -1,6 +1,9 @@
 package tree_sitter_typescript_test
 
 import (
+	"encoding/json"
+	"os"
+	"strings"
 	"testing"
 
 	tree_sitter "github.com/tree-sitter/go-tree-sitter"
@@ -13,3 +16,410 @@ func TestCanLoadGrammar(t *testing.T) {
 		t.Errorf("Error loading Typescript grammar")
 	}
 }
+
+func TestNodeTypes(t *testing.T) {
+	var nodeTypes []struct {
+		Type  string `json:"type"`
+		Named bool   `json:"named"`
+	}
+	if err := json.Unmarshal(tree_sitter_typescript.NodeTypes(), &nodeTypes); err != nil {
+		t.Fatalf("Error parsing node types: %v", err)
+	}
+
+	named := make(map[string]bool)
+	for _, nodeType := range nodeTypes {
+		if nodeType.Named {
+			named[nodeType.Type] = true
+		}
+	}
+	for _, want := range []string{
+		"class_declaration",
+		"using_declaration",
+		"class_accessor_definition",
+		"decorator",
+		"non_null_expression",
+		"import_alias",
+		"enum_declaration",
+		"overload_signature",
+	} {
+		if !named[want] {
+			t.Errorf("Missing %s in node types", want)
+		}
+	}
+}
+
+func TestNodeTypesMatchGrammar(t *testing.T) {
+	generated, err := os.ReadFile("../../src/node-types.json")
+	if err != nil {
+		t.Fatalf("Error reading generated node types: %v", err)
+	}
+	if string(tree_sitter_typescript.NodeTypes()) != string(generated) {
+		t.Errorf("Embedded node-types.json differs from src/node-types.json, run go generate")
+	}
+}
+
+func TestIncrementalParse(t *testing.T) {
+	parser := tree_sitter.NewParser()
+	defer parser.Close()
+	if err := parser.SetLanguage(tree_sitter.NewLanguage(tree_sitter_typescript.Language())); err != nil {
+		t.Fatalf("Error setting language: %v", err)
+	}
+
+	tests := []struct {
+		source   string
+		after    string
+		inserted string
+	}{
+		{"x = `a ${`b ${c} d`} e`;", "${c", " + f"},
+		{"x = `a ${`b ${c} d`} e`;", "`b", " zz"},
+		{"x = <div>{<span>{a}</span>}</div>;", "{a", " + b"},
+		{"x = <div>{<span>text</span>}</div>;", "<span>te", "x"},
+	}
+
+	for _, test := range tests {
+		offset := uint(strings.Index(test.source, test.after) + len(test.after))
+		edited := test.source[:offset] + test.inserted + test.source[offset:]
+		newEnd := offset + uint(len(test.inserted))
+
+		tree := parser.Parse([]byte(test.source), nil)
+		tree.Edit(&tree_sitter.InputEdit{
+			StartByte:      offset,
+			OldEndByte:     offset,
+			NewEndByte:     newEnd,
+			StartPosition:  tree_sitter.NewPoint(0, offset),
+			OldEndPosition: tree_sitter.NewPoint(0, offset),
+			NewEndPosition: tree_sitter.NewPoint(0, newEnd),
+		})
+		incremental := parser.Parse([]byte(edited), tree)
+		full := parser.Parse([]byte(edited), nil)
+
+		if full.RootNode().HasError() {
+			t.Errorf("Unexpected error parsing %q: %s", edited, full.RootNode().ToSexp())
+		}
+		if got, want := incremental.RootNode().ToSexp(), full.RootNode().ToSexp(); got != want {
+			t.Errorf("Incremental parse of %q differs:\n got: %s\nwant: %s", edited, got, want)
+		}
+
+		tree.Close()
+		incremental.Close()
+		full.Close()
+	}
+}
+
+func TestEnumConstField(t *testing.T) {
+	parser := tree_sitter.NewParser()
+	defer parser.Close()
+	if err := parser.SetLanguage(tree_sitter.NewLanguage(tree_sitter_typescript.Language())); err != nil {
+		t.Fatalf("Error setting language: %v", err)
+	}
+
+	tests := []struct {
+		source  string
+		isConst bool
+	}{
+		{"const enum E { A }", true},
+		{"enum E { A }", false},
+	}
+
+	for _, test := range tests {
+		tree := parser.Parse([]byte(test.source), nil)
+		root := tree.RootNode()
+		if root.HasError() {
+			t.Errorf("Unexpected error parsing %q: %s", test.source, root.ToSexp())
+		}
+		enum := root.NamedChild(0).NamedChild(0).NamedChild(0)
+		if enum.Kind() != "enum_declaration" {
+			t.Fatalf("Expected enum_declaration in %q, got %s", test.source, enum.Kind())
+		}
+		if got := enum.ChildByFieldName("const") != nil; got != test.isConst {
+			t.Errorf("Const field of %q: got %v, want %v", test.source, got, test.isConst)
+		}
+		tree.Close()
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	languages := tree_sitter_typescript.Languages()
+	if len(languages) != 1 {
+		t.Errorf("Expected 1 language, got %d", len(languages))
+	}
+	for name, pointer := range languages {
+		if tree_sitter.NewLanguage(pointer) == nil {
+			t.Errorf("Error loading %s grammar", name)
+		}
+	}
+	if _, ok := languages["typescript"]; !ok {
+		t.Errorf("Missing typescript grammar")
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	metadata := tree_sitter_typescript.Metadata()
+	if metadata.ABIVersion < tree_sitter.MIN_COMPATIBLE_LANGUAGE_VERSION || metadata.ABIVersion > tree_sitter.LANGUAGE_VERSION {
+		t.Errorf("Unsupported ABI version %d", metadata.ABIVersion)
+	}
+	language := tree_sitter.NewLanguage(tree_sitter_typescript.Language())
+	if metadata.ABIVersion != language.AbiVersion() {
+		t.Errorf("ABI version %d does not match the language's %d", metadata.ABIVersion, language.AbiVersion())
+	}
+	// Matches metadata.version in tree-sitter.json.
+	if metadata.GrammarVersion != "0.0.1" {
+		t.Errorf("Expected grammar version 0.0.1, got %q", metadata.GrammarVersion)
+	}
+}
+
+func TestTaggedTemplateInjections(t *testing.T) {
+	tests := []struct {
+		source string
+		want   []string
+	}{
+		{"x = css`color: red;`;", []string{"css: color: red;"}},
+		{"x = styled.div`color: red;`;", []string{"css: color: red;"}},
+		{"x = format`color: red;`;", nil},
+		{"x = `color: red;`;", nil},
+	}
+
+	for _, test := range tests {
+		got := injections(t, test.source)
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("Injections in %q:\n got: %q\nwant: %q", test.source, got, test.want)
+		}
+	}
+}
+
+func TestJSDocInjections(t *testing.T) {
+	tests := []struct {
+		source string
+		want   []string
+	}{
+		{"/** Adds numbers. */\nf();", []string{"jsdoc: /** Adds numbers. */"}},
+		{"/* Adds numbers. */\nf();", nil},
+		{"// Adds numbers.\nf();", nil},
+		{"/**/\nf();", nil},
+	}
+
+	for _, test := range tests {
+		got := injections(t, test.source)
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("Injections in %q:\n got: %q\nwant: %q", test.source, got, test.want)
+		}
+	}
+}
+
+func TestLocals(t *testing.T) {
+	tests := []struct {
+		source      string
+		definitions []string
+		references  []string
+	}{
+		{"function f(a, { b, c: d }, [e, ...g], h = 1) {}", []string{"a", "b", "d", "e", "g", "h"}, nil},
+		{"const { i, j: k = 1 } = o, [l, ...m] = p;", []string{"i", "k", "l", "m"}, []string{"o", "p"}},
+		{"[a, b] = c;", nil, []string{"a", "b", "c"}},
+		{"({ a, b } = c);", nil, []string{"c"}},
+		{"[...r] = s;", nil, []string{"r", "s"}},
+		{"function f({ a: { b } }, [c, [d = 1, ...e]]) {}", []string{"b", "c", "d", "e"}, nil},
+		{"([x, [y]]) => x + y;", []string{"x", "y"}, []string{"x", "y"}},
+		{"const { a: { b: [c] } } = o;", []string{"c"}, []string{"o"}},
+		{"try {} catch ({ message, cause: [first] }) {}", []string{"message", "first"}, nil},
+		{"({ a: [b] } = c);", nil, []string{"b", "c"}},
+		{"[a = 1, [b]] = c;", nil, []string{"a", "b", "c"}},
+		{"function h({ a, ...rest }) {}", []string{"a", "rest"}, nil},
+		{"const { a: { b, ...inner }, ...outer } = o;", []string{"b", "inner", "outer"}, []string{"o"}},
+		{"({ a, ...rest } = o);", nil, []string{"rest", "o"}},
+	}
+
+	for _, test := range tests {
+		var definitions []string
+		references := map[string]bool{}
+		query, matches := runQuery(t, "locals.scm", test.source)
+		names := query.CaptureNames()
+		for match := matches.Next(); match != nil; match = matches.Next() {
+			for _, capture := range match.Captures {
+				text := capture.Node.Utf8Text([]byte(test.source))
+				switch names[capture.Index] {
+				case "local.definition":
+					definitions = append(definitions, text)
+				case "local.reference":
+					references[text] = true
+				}
+			}
+		}
+		if strings.Join(definitions, " ") != strings.Join(test.definitions, " ") {
+			t.Errorf("Definitions in %q:\n got: %q\nwant: %q", test.source, definitions, test.definitions)
+		}
+		for _, want := range test.references {
+			if !references[want] {
+				t.Errorf("Missing reference to %s in %q", want, test.source)
+			}
+		}
+	}
+}
+
+func TestLocalsResolution(t *testing.T) {
+	// Each reference is the nth occurrence of a name in the source, and
+	// resolves to the definition at another occurrence of the same name.
+	type resolution struct {
+		name                  string
+		reference, definition int
+	}
+	tests := []struct {
+		source string
+		want   []resolution
+	}{
+		{
+			"const value = 1; const f = (value) => (inner = value) => value => value + inner; value;",
+			[]resolution{{"value", 2, 1}, {"value", 4, 3}, {"inner", 1, 0}, {"value", 5, 0}},
+		},
+		{
+			"const list = 1; const g = ([list, { list: [item] }]) => list + item; list;",
+			[]resolution{{"list", 3, 1}, {"item", 1, 0}, {"list", 4, 0}},
+		},
+		{
+			"function h(error) { try {} catch (error) { error; } error; }",
+			[]resolution{{"error", 2, 1}, {"error", 3, 0}},
+		},
+	}
+
+	for _, test := range tests {
+		resolved := resolveLocals(t, test.source)
+		for _, want := range test.want {
+			reference := nthIndex(test.source, want.name, want.reference)
+			definition := nthIndex(test.source, want.name, want.definition)
+			got, ok := resolved[reference]
+			if !ok {
+				t.Errorf("Reference %d to %s in %q is not resolved", want.reference, want.name, test.source)
+			} else if got != definition {
+				t.Errorf("Reference %d to %s in %q resolves to byte %d, want %d", want.reference, want.name, test.source, got, definition)
+			}
+		}
+	}
+}
+
+// Run queries/locals.scm over source and resolve every reference the way a
+// highlighter does: look through the enclosing scopes from the innermost
+// out, and take the last definition of the name before the reference. The
+// result maps the start byte of each resolved reference to the start byte
+// of its definition.
+func resolveLocals(t *testing.T, source string) map[uint]uint {
+	t.Helper()
+	var scopes, definitions, references []tree_sitter.Node
+	query, matches := runQuery(t, "locals.scm", source)
+	names := query.CaptureNames()
+	for match := matches.Next(); match != nil; match = matches.Next() {
+		for _, capture := range match.Captures {
+			switch names[capture.Index] {
+			case "local.scope":
+				scopes = append(scopes, capture.Node)
+			case "local.definition":
+				definitions = append(definitions, capture.Node)
+			case "local.reference":
+				references = append(references, capture.Node)
+			}
+		}
+	}
+
+	// The innermost scope around a node other than the scope at skip, or -1
+	// for the top level.
+	scopeOf := func(node tree_sitter.Node, skip int) int {
+		inner := -1
+		for i, scope := range scopes {
+			if i != skip && scope.StartByte() <= node.StartByte() && node.EndByte() <= scope.EndByte() &&
+				(inner < 0 || scopes[inner].StartByte() <= scope.StartByte() && scope.EndByte() <= scopes[inner].EndByte()) {
+				inner = i
+			}
+		}
+		return inner
+	}
+	isDefinition := map[uint]bool{}
+	for _, definition := range definitions {
+		isDefinition[definition.StartByte()] = true
+	}
+
+	resolved := map[uint]uint{}
+	for _, reference := range references {
+		if isDefinition[reference.StartByte()] {
+			continue
+		}
+		name := reference.Utf8Text([]byte(source))
+		for scope := scopeOf(reference, -1); ; {
+			found := false
+			for _, definition := range definitions {
+				if scopeOf(definition, -1) == scope && definition.StartByte() < reference.StartByte() &&
+					definition.Utf8Text([]byte(source)) == name {
+					resolved[reference.StartByte()] = definition.StartByte()
+					found = true
+				}
+			}
+			if found || scope < 0 {
+				break
+			}
+			scope = scopeOf(scopes[scope], scope)
+		}
+	}
+	return resolved
+}
+
+// The byte offset of the nth occurrence of name in source, counting from 0.
+func nthIndex(source, name string, n int) uint {
+	offset := 0
+	for ; n > 0; n-- {
+		offset += strings.Index(source[offset:], name) + len(name)
+	}
+	return uint(offset + strings.Index(source[offset:], name))
+}
+
+// Run queries/injections.scm over source and return each injected node as
+// "language: text", in match order.
+func injections(t *testing.T, source string) []string {
+	t.Helper()
+	var result []string
+	query, matches := runQuery(t, "injections.scm", source)
+	names := query.CaptureNames()
+	for match := matches.Next(); match != nil; match = matches.Next() {
+		injected := ""
+		for _, property := range query.PropertySettings(match.PatternIndex) {
+			if property.Key == "injection.language" && property.Value != nil {
+				injected = *property.Value
+			}
+		}
+		for _, capture := range match.Captures {
+			if names[capture.Index] == "injection.language" {
+				injected = capture.Node.Utf8Text([]byte(source))
+			}
+		}
+		for _, capture := range match.Captures {
+			if names[capture.Index] == "injection.content" {
+				result = append(result, injected+": "+capture.Node.Utf8Text([]byte(source)))
+			}
+		}
+	}
+	return result
+}
+
+// Parse source and run the named file from queries/ over it. Everything is
+// closed when the test finishes.
+func runQuery(t *testing.T, file string, source string) (*tree_sitter.Query, tree_sitter.QueryMatches) {
+	t.Helper()
+	language := tree_sitter.NewLanguage(tree_sitter_typescript.Language())
+	contents, err := os.ReadFile("../../queries/" + file)
+	if err != nil {
+		t.Fatalf("Error reading %s: %v", file, err)
+	}
+	query, queryErr := tree_sitter.NewQuery(language, string(contents))
+	if queryErr != nil {
+		t.Fatalf("Error parsing %s: %v", file, queryErr)
+	}
+	t.Cleanup(query.Close)
+
+	parser := tree_sitter.NewParser()
+	t.Cleanup(parser.Close)
+	if err := parser.SetLanguage(language); err != nil {
+		t.Fatalf("Error setting language: %v", err)
+	}
+	tree := parser.Parse([]byte(source), nil)
+	t.Cleanup(tree.Close)
+
+	cursor := tree_sitter.NewQueryCursor()
+	t.Cleanup(cursor.Close)
+	return query, cursor.Matches(query, tree.RootNode(), []byte(source))
+}
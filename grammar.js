@@ -8,7 +8,7 @@
 // @ts-check
 
 module.exports = grammar({
-  name: 'javascript',
+  name: 'typescript',
 
   externals: $ => [
     $._automatic_semicolon,
@@ -19,6 +19,10 @@ module.exports = grammar({
     $.escape_sequence,
     $.regex_pattern,
     $.jsx_text,
+    $._no_line_break,
+    $._arrow_no_line_break,
+    $._reference_directive_start,
+    $._function_signature_automatic_semicolon,
   ],
 
   extras: $ => [
@@ -49,6 +53,7 @@ module.exports = grammar({
       'member',
       'template_call',
       'call',
+      'non_null',
       $.update_expression,
       'unary_void',
       'binary_times',
@@ -72,13 +77,19 @@ module.exports = grammar({
     [$.primary_expression, $.statement_block, 'object'],
     [$.meta_property, $.import],
     [$.import_statement, $.import],
+    [$.import_alias, $.import],
     [$.export_statement, $.primary_expression],
+    [$.export_assignment, $.primary_expression],
     [$.lexical_declaration, $.primary_expression],
+    [$.using_declaration, $.primary_expression],
+    [$.decorator_call_expression, $.decorator],
   ],
 
   conflicts: $ => [
     [$.primary_expression, $._property_name],
     [$.primary_expression, $.method_definition],
+    [$.primary_expression, $.override_modifier],
+    [$._module_identifier, $.type_modifier],
     [$.primary_expression, $.rest_pattern],
     [$.primary_expression, $.pattern],
     [$.primary_expression, $._for_header],
@@ -90,6 +101,7 @@ module.exports = grammar({
     [$.labeled_statement, $._property_name],
     [$.computed_property_name, $.array],
     [$.binary_expression, $._initializer],
+    [$.class],
   ],
 
   word: $ => $.identifier,
@@ -97,11 +109,36 @@ module.exports = grammar({
   rules: {
     program: $ => seq(
       optional($.hash_bang_line),
+      repeat($.triple_slash_directive),
       repeat($.statement),
     ),
 
     hash_bang_line: _ => /#!.*/,
 
+    triple_slash_directive: $ => seq(
+      $._reference_directive_start,
+      repeat1(field('attribute', $.directive_attribute)),
+      '/>',
+      optional($._directive_trailer),
+    ),
+
+    directive_attribute: $ => seq(
+      field('name', $.directive_attribute_name),
+      '=',
+      field('value', $.string),
+    ),
+
+    directive_attribute_name: _ => choice(
+      'types',
+      'path',
+      'lib',
+      'no-default-lib',
+      'resolution-mode',
+      'preserve',
+    ),
+
+    _directive_trailer: _ => token.immediate(/[ \t]*[^ \t\r\n][^\r\n]*/),
+
     export_statement: $ => choice(
       seq(
         'export',
@@ -113,6 +150,7 @@ module.exports = grammar({
         $._semicolon,
       ),
       seq(
+        repeat(field('decorator', $.decorator)),
         'export',
         choice(
           field('declaration', $.declaration),
@@ -130,6 +168,13 @@ module.exports = grammar({
       ),
     ),
 
+    export_assignment: $ => seq(
+      'export',
+      '=',
+      field('value', $.expression),
+      $._semicolon,
+    ),
+
     export_clause: $ => seq(
       '{',
       optional(sepBy(',', $.export_specifier)),
@@ -138,19 +183,29 @@ module.exports = grammar({
     ),
 
     export_specifier: $ => seq(
-      field('name', $.identifier),
+      optional(field('kind', $.type_modifier)),
+      field('name', $._module_identifier),
       optional(seq(
         'as',
-        field('alias', $.identifier),
+        field('alias', $._module_identifier),
       )),
     ),
 
+    _module_identifier: $ => choice(
+      $.identifier,
+      alias(choice('type', 'as'), $.identifier),
+    ),
+
     declaration: $ => choice(
       $.function_declaration,
+      $.overload_signature,
       $.generator_function_declaration,
       $.class_declaration,
+      $.enum_declaration,
       $.lexical_declaration,
+      $.using_declaration,
       $.variable_declaration,
+      $.import_alias,
     ),
 
     import: _ => token('import'),
@@ -159,11 +214,32 @@ module.exports = grammar({
       'import',
       choice(
         seq($.import_clause, $._from_clause),
-        field('source', $.string),
+        $.import_require_clause,
+        seq(
+          field('source', $.string),
+          optional(field('attributes', $.import_attribute)),
+        ),
       ),
       $._semicolon,
     ),
 
+    import_require_clause: $ => seq(
+      field('name', $.identifier),
+      '=',
+      'require',
+      '(',
+      field('source', $.string),
+      ')',
+    ),
+
+    import_alias: $ => seq(
+      'import',
+      field('name', $.identifier),
+      '=',
+      field('value', choice($.identifier, alias($.nested_identifier, $.member_expression))),
+      $._semicolon,
+    ),
+
     import_clause: $ => choice(
       $.namespace_import,
       $.named_imports,
@@ -181,6 +257,30 @@ module.exports = grammar({
 
     _from_clause: $ => seq(
       'from', field('source', $.string),
+      optional(field('attributes', $.import_attribute)),
+    ),
+
+    import_attribute: $ => seq(
+      choice('with', 'assert'),
+      alias($._import_attribute_entries, $.object),
+    ),
+
+    _import_attribute_entries: $ => seq(
+      '{',
+      optional(sepBy(',', optional(alias($._import_attribute_entry, $.pair)))),
+      '}',
+    ),
+
+    _import_attribute_entry: $ => seq(
+      field('key', choice(
+        alias(
+          choice($.identifier, $._reserved_identifier),
+          $.property_identifier,
+        ),
+        $.string,
+      )),
+      ':',
+      field('value', $.string),
     ),
 
     namespace_import: $ => seq(
@@ -194,17 +294,21 @@ module.exports = grammar({
       '}',
     ),
 
-    import_specifier: $ => choice(
-      field('name', $.identifier),
-      seq(
-        field('name', $.identifier),
-        'as',
-        field('alias', $.identifier),
+    import_specifier: $ => seq(
+      optional(field('kind', $.type_modifier)),
+      choice(
+        field('name', $._module_identifier),
+        seq(
+          field('name', $._module_identifier),
+          'as',
+          field('alias', $._module_identifier),
+        ),
       ),
     ),
 
     statement: $ => choice(
       $.export_statement,
+      $.export_assignment,
       $.import_statement,
       $.debugger_statement,
       $.expression_statement,
@@ -245,6 +349,17 @@ module.exports = grammar({
       $._semicolon,
     ),
 
+    using_declaration: $ => seq(
+      field('kind', choice('using', seq('await', 'using'))),
+      sepBy(',', alias($._using_declarator, $.variable_declarator)),
+      $._semicolon,
+    ),
+
+    _using_declarator: $ => seq(
+      field('name', $.identifier),
+      optional($._initializer),
+    ),
+
     variable_declarator: $ => seq(
       field('name', choice($.identifier, $._destructuring_pattern)),
       optional($._initializer),
@@ -301,6 +416,8 @@ module.exports = grammar({
         field('left', choice(
           $._lhs_expression,
           $.parenthesized_expression,
+          $.array,
+          $.object,
         )),
         seq(
           field('kind', 'var'),
@@ -376,6 +493,7 @@ module.exports = grammar({
 
     throw_statement: $ => seq(
       'throw',
+      $._no_line_break,
       $._expressions,
       $._semicolon,
     ),
@@ -465,20 +583,26 @@ module.exports = grammar({
       $.class,
       $.meta_property,
       $.call_expression,
+      $.non_null_expression,
     ),
 
     yield_expression: $ => prec.right(seq(
       'yield',
-      choice(
-        seq('*', $.expression),
-        optional($.expression),
-      ))),
+      optional(seq(
+        $._no_line_break,
+        choice(
+          seq('*', $.expression),
+          $.expression,
+        ),
+      )),
+    )),
 
     object: $ => prec('object', seq(
       '{',
       optional(sepBy(',', optional(choice(
         $.pair,
         $.method_definition,
+        $.spread_element,
         alias(
           choice($.identifier, $._reserved_identifier),
           $.shorthand_property_identifier,
@@ -491,6 +615,7 @@ module.exports = grammar({
       '{',
       optional(sepBy(',', optional(choice(
         $.pair_pattern,
+        $.rest_pattern,
         $.object_assignment_pattern,
         alias(
           choice($.identifier, $._reserved_identifier),
@@ -582,7 +707,11 @@ module.exports = grammar({
       field('property', alias($.identifier, $.property_identifier)),
     )),
 
-    jsx_namespace_name: $ => seq($._jsx_identifier, ':', $._jsx_identifier),
+    jsx_namespace_name: $ => seq(
+      field('namespace', $._jsx_identifier),
+      ':',
+      field('name', $._jsx_identifier),
+    ),
 
     _jsx_element_name: $ => choice(
       $._jsx_identifier,
@@ -645,6 +774,7 @@ module.exports = grammar({
     ),
 
     class: $ => prec('literal', seq(
+      repeat(field('decorator', $.decorator)),
       'class',
       field('name', optional($.identifier)),
       optional($.class_heritage),
@@ -652,6 +782,7 @@ module.exports = grammar({
     )),
 
     class_declaration: $ => prec('declaration', seq(
+      repeat(field('decorator', $.decorator)),
       'class',
       field('name', $.identifier),
       optional($.class_heritage),
@@ -661,6 +792,30 @@ module.exports = grammar({
 
     class_heritage: $ => seq('extends', $.expression),
 
+    enum_declaration: $ => seq(
+      optional(field('const', 'const')),
+      'enum',
+      field('name', $.identifier),
+      field('body', $.enum_body),
+    ),
+
+    enum_body: $ => seq(
+      '{',
+      optional(seq(
+        sepBy(',', choice(
+          field('name', $._property_name),
+          $.enum_assignment,
+        )),
+        optional(','),
+      )),
+      '}',
+    ),
+
+    enum_assignment: $ => seq(
+      field('name', $._property_name),
+      $._initializer,
+    ),
+
     function_expression: $ => prec('literal', seq(
       'function',
       field('name', optional($.identifier)),
@@ -676,6 +831,13 @@ module.exports = grammar({
       optional($._automatic_semicolon),
     )),
 
+    overload_signature: $ => seq(
+      'function',
+      field('name', $.identifier),
+      $._call_signature,
+      $._signature_semicolon,
+    ),
+
     generator_function: $ => prec('literal', seq(
       'function',
       '*',
@@ -701,6 +863,7 @@ module.exports = grammar({
         )),
         $._call_signature,
       ),
+      $._arrow_no_line_break,
       '=>',
       field('body', choice(
         $.expression,
@@ -716,10 +879,19 @@ module.exports = grammar({
         field('function', $.expression),
         field('arguments', $.arguments),
       )),
+      prec('call', seq(
+        field('function', $.import),
+        field('arguments', alias($._import_arguments, $.arguments)),
+      )),
       prec('template_call', seq(
         field('function', choice($.primary_expression, $.new_expression)),
         field('arguments', $.template_string),
       )),
+      prec('member', seq(
+        field('function', $.primary_expression),
+        field('optional_chain', $.optional_chain),
+        field('arguments', $.arguments),
+      )),
     ),
 
     new_expression: $ => prec.right('new', seq(
@@ -730,25 +902,39 @@ module.exports = grammar({
 
     member_expression: $ => prec('member', seq(
       field('object', choice($.expression, $.primary_expression, $.import)),
-      '.',
+      choice('.', field('optional_chain', $.optional_chain)),
       field('property', alias($.identifier, $.property_identifier)),
     )),
 
     subscript_expression: $ => prec.right('member', seq(
       field('object', choice($.expression, $.primary_expression)),
+      optional(field('optional_chain', $.optional_chain)),
       '[', field('index', $._expressions), ']',
     )),
 
+    optional_chain: _ => '?.',
+
+    non_null_expression: $ => prec.left('non_null', seq(
+      field('argument', $.expression),
+      '!',
+    )),
+
     _lhs_expression: $ => choice(
       $.member_expression,
       $.subscript_expression,
+      $.non_null_expression,
       $._identifier,
       alias($._reserved_identifier, $.identifier),
       $._destructuring_pattern,
     ),
 
     assignment_expression: $ => prec.right('assign', seq(
-      field('left', choice($.parenthesized_expression, $._lhs_expression)),
+      field('left', choice(
+        $.parenthesized_expression,
+        $._lhs_expression,
+        $.array,
+        $.object,
+      )),
       '=',
       field('right', $.expression),
     )),
@@ -764,7 +950,7 @@ module.exports = grammar({
     augmented_assignment_expression: $ => prec.right('assign', seq(
       field('left', $._augmented_assignment_lhs),
       field('operator', choice('+=', '-=', '*=', '/=', '%=', '^=', '&=', '|=', '>>=', '>>>=',
-        '<<=')),
+        '<<=', '&&=', '||=', '??=')),
       field('right', $.expression),
     )),
 
@@ -773,10 +959,10 @@ module.exports = grammar({
       field('value', $.expression),
     ),
 
-    _destructuring_pattern: $ => choice(
+    _destructuring_pattern: $ => prec.dynamic(-1, choice(
       $.object_pattern,
       $.array_pattern,
-    ),
+    )),
 
     spread_element: $ => seq('...', $.expression),
 
@@ -792,6 +978,7 @@ module.exports = grammar({
       ...[
         ['&&', 'logical_and'],
         ['||', 'logical_or'],
+        ['??', 'ternary'],
         ['>>', 'binary_shift'],
         ['>>>', 'binary_shift'],
         ['<<', 'binary_shift'],
@@ -927,27 +1114,34 @@ module.exports = grammar({
     number: _ => {
       const hexLiteral = seq(
         choice('0x', '0X'),
-        /[\da-fA-F]+/,
+        /[\da-fA-F](_?[\da-fA-F])*/,
       );
 
-      const decimalDigits = /\d+/;
+      const decimalDigits = /\d(_?\d)*/;
       const signedInteger = seq(optional(choice('-', '+')), decimalDigits);
       const exponentPart = seq(choice('e', 'E'), signedInteger);
 
-      const binaryLiteral = seq(choice('0b', '0B'), /[0-1]+/);
+      const binaryLiteral = seq(choice('0b', '0B'), /[0-1](_?[0-1])*/);
 
-      const octalLiteral = seq(choice('0o', '0O'), /[0-7]+/);
+      const octalLiteral = seq(choice('0o', '0O'), /[0-7](_?[0-7])*/);
 
       const decimalIntegerLiteral = choice(
         '0',
-        seq(optional('0'), /[1-9]/, optional(decimalDigits)),
+        seq(/[1-9]/, optional(seq(optional('_'), decimalDigits))),
       );
 
+      // Legacy literals such as 010 and 08 take neither separators nor n.
+      const legacyIntegerLiteral = /0\d+/;
+
+      const integerPart = choice(decimalIntegerLiteral, legacyIntegerLiteral);
+
+      const bigintLiteral = seq(choice(hexLiteral, binaryLiteral, octalLiteral, decimalIntegerLiteral), 'n');
+
       const decimalLiteral = choice(
-        seq(decimalIntegerLiteral, '.', optional(decimalDigits), optional(exponentPart)),
+        seq(integerPart, '.', optional(decimalDigits), optional(exponentPart)),
         seq('.', decimalDigits, optional(exponentPart)),
-        seq(decimalIntegerLiteral, exponentPart),
-        decimalDigits,
+        seq(integerPart, exponentPart),
+        integerPart,
       );
 
       return token(choice(
@@ -955,6 +1149,7 @@ module.exports = grammar({
         decimalLiteral,
         binaryLiteral,
         octalLiteral,
+        bigintLiteral,
       ));
     },
 
@@ -985,10 +1180,20 @@ module.exports = grammar({
       ')',
     ),
 
+    _import_arguments: $ => seq(
+      '(',
+      $.expression,
+      optional(seq(',', field('attributes', $.expression))),
+      optional(','),
+      ')',
+    ),
+
     class_body: $ => seq(
       '{',
       repeat(choice(
         seq(field('member', $.method_definition), optional(';')),
+        seq(field('member', alias($._method_signature, $.overload_signature)), $._signature_semicolon),
+        seq(field('member', $.class_accessor_definition), $._semicolon),
         ';',
       )),
       '}',
@@ -996,7 +1201,10 @@ module.exports = grammar({
 
     formal_parameters: $ => seq(
       '(',
-      optional(sepBy(',', $._formal_parameter)),
+      optional(sepBy(',', seq(
+        repeat(field('decorator', $.decorator)),
+        $._formal_parameter,
+      ))),
       ')',
     ),
 
@@ -1011,13 +1219,56 @@ module.exports = grammar({
     )),
 
     method_definition: $ => seq(
+      repeat(field('decorator', $.decorator)),
       optional('static'),
+      optional($.override_modifier),
       optional(choice('get', 'set', '*')),
       field('name', $._property_name),
       field('parameters', $.formal_parameters),
       field('body', $.statement_block),
     ),
 
+    _method_signature: $ => seq(
+      repeat(field('decorator', $.decorator)),
+      optional('static'),
+      optional($.override_modifier),
+      optional('*'),
+      field('name', $._property_name),
+      field('parameters', $.formal_parameters),
+    ),
+
+    class_accessor_definition: $ => seq(
+      repeat(field('decorator', $.decorator)),
+      optional('static'),
+      optional($.override_modifier),
+      'accessor',
+      field('property', $._property_name),
+      optional($._initializer),
+    ),
+
+    override_modifier: _ => 'override',
+
+    type_modifier: _ => 'type',
+
+    decorator: $ => seq(
+      '@',
+      field('expression', choice(
+        $.identifier,
+        alias($.nested_identifier, $.member_expression),
+        alias($.decorator_call_expression, $.call_expression),
+        $.parenthesized_expression,
+      )),
+    ),
+
+    decorator_call_expression: $ => prec('call', seq(
+      field('function', choice(
+        $.identifier,
+        alias($.nested_identifier, $.member_expression),
+        alias($.decorator_call_expression, $.call_expression),
+      )),
+      field('arguments', $.arguments),
+    )),
+
     pair: $ => seq(
       field('key', $._property_name),
       ':',
@@ -1052,9 +1303,15 @@ module.exports = grammar({
       'static',
       'export',
       'let',
+      'using',
+      'await',
+      'accessor',
+      'override',
     ),
 
     _semicolon: $ => choice($._automatic_semicolon, ';'),
+
+    _signature_semicolon: $ => choice($._function_signature_automatic_semicolon, ';'),
   },
 });
 
@@ -0,0 +1,25 @@
+; Compiler directives
+; -------------------
+; Only comments that begin with the directive count, so prose that merely
+; mentions one stays an ordinary comment.
+
+((comment) @comment.directive
+  (#match? @comment.directive "^(//|/\\*+)[ \t]*@ts-(expect-error|ignore|nocheck|check)([^A-Za-z0-9-]|$)"))
+
+(comment) @comment
+(html_comment) @comment
+
+; JSX
+; ---
+; Embedded `{...}` expressions are left to the TypeScript patterns.
+
+(jsx_opening_element name: (_) @tag)
+(jsx_closing_element name: (_) @tag)
+(jsx_self_closing_element name: (_) @tag)
+
+(jsx_attribute (property_identifier) @attribute)
+(jsx_attribute (jsx_namespace_name) @attribute)
+
+(jsx_opening_element ["<" ">"] @punctuation.bracket)
+(jsx_closing_element ["</" ">"] @punctuation.bracket)
+(jsx_self_closing_element ["<" "/>"] @punctuation.bracket)
@@ -0,0 +1,65 @@
+; Tagged template literals
+; ------------------------
+; Only the string fragments are injected, so `${...}` substitutions keep
+; being parsed as TypeScript.
+
+(call_expression
+  function: (primary_expression
+    (identifier) @injection.language)
+  arguments: (template_string
+    (string_fragment) @injection.content)
+  (#any-of? @injection.language "css" "html" "sql" "graphql")
+  (#set! injection.combined))
+
+(call_expression
+  function: (primary_expression
+    (identifier) @_tag)
+  arguments: (template_string
+    (string_fragment) @injection.content)
+  (#eq? @_tag "gql")
+  (#set! injection.language "graphql")
+  (#set! injection.combined))
+
+(call_expression
+  function: (primary_expression
+    (identifier) @_tag)
+  arguments: (template_string
+    (string_fragment) @injection.content)
+  (#any-of? @_tag "keyframes" "createGlobalStyle" "injectGlobal")
+  (#set! injection.language "css")
+  (#set! injection.combined))
+
+; styled.div`...`, styled(Button)`...` and styled.div.attrs(...)`...`
+
+(call_expression
+  function: (primary_expression
+    [
+      (member_expression
+        object: (primary_expression
+          (identifier) @_tag))
+      (call_expression
+        function: (expression
+          (primary_expression
+            (identifier) @_tag)))
+      (call_expression
+        function: (expression
+          (primary_expression
+            (member_expression
+              object: (primary_expression
+                (member_expression
+                  object: (primary_expression
+                    (identifier) @_tag)))))))
+    ])
+  arguments: (template_string
+    (string_fragment) @injection.content)
+  (#eq? @_tag "styled")
+  (#set! injection.language "css")
+  (#set! injection.combined))
+
+; JSDoc
+; -----
+; Only `/**` block comments are documentation; `/**/` and `/***` are not.
+
+((comment) @injection.content
+  (#match? @injection.content "^/\\*\\*[^*/]")
+  (#set! injection.language "jsdoc"))
@@ -0,0 +1,40 @@
+; Scopes
+;-------
+
+[
+  (statement_block)
+  (function_expression)
+  (arrow_function)
+  (function_declaration)
+  (generator_function)
+  (generator_function_declaration)
+  (method_definition)
+  (catch_clause)
+] @local.scope
+
+; Definitions
+;------------
+; Destructuring assignments such as `[a, b] = c` parse as array and object
+; expressions, so patterns only appear where names are bound.
+
+(pattern
+  (identifier) @local.definition)
+
+(rest_pattern
+  (identifier) @local.definition)
+
+(shorthand_property_identifier_pattern) @local.definition
+
+(arrow_function
+  parameter: (identifier) @local.definition)
+
+(variable_declarator
+  name: (identifier) @local.definition)
+
+(catch_clause
+  parameter: (identifier) @local.definition)
+
+; References
+;------------
+
+(identifier) @local.reference
@@ -1,6 +1,7 @@
 #include "tree_sitter/parser.h"
 
 #include <stdio.h>
+#include <string.h>
 #include <wctype.h>
 
 enum TokenType {
@@ -12,15 +13,19 @@ enum TokenType {
     ESCAPE_SEQUENCE,
     REGEX_PATTERN,
     JSX_TEXT,
+    NO_LINE_BREAK,
+    ARROW_NO_LINE_BREAK,
+    REFERENCE_DIRECTIVE_START,
+    FUNCTION_SIGNATURE_AUTOMATIC_SEMICOLON,
 };
 
-void *tree_sitter_javascript_external_scanner_create() { return NULL; }
+void *tree_sitter_typescript_external_scanner_create() { return NULL; }
 
-void tree_sitter_javascript_external_scanner_destroy(void *p) {}
+void tree_sitter_typescript_external_scanner_destroy(void *p) {}
 
-unsigned tree_sitter_javascript_external_scanner_serialize(void *payload, char *buffer) { return 0; }
+unsigned tree_sitter_typescript_external_scanner_serialize(void *payload, char *buffer) { return 0; }
 
-void tree_sitter_javascript_external_scanner_deserialize(void *p, const char *b, unsigned n) {}
+void tree_sitter_typescript_external_scanner_deserialize(void *p, const char *b, unsigned n) {}
 
 static inline void advance(TSLexer *lexer) { lexer->advance(lexer, false); }
 
@@ -107,7 +112,73 @@ static WhitespaceResult scan_whitespace_and_comments(TSLexer *lexer, bool *scann
     }
 }
 
-static bool scan_automatic_semicolon(TSLexer *lexer, bool comment_condition, bool *scanned_comment) {
+static inline bool is_line_terminator(int32_t c) { return c == '\n' || c == '\r' || c == 0x2028 || c == 0x2029; }
+
+// Whether c can begin the operand of a restricted production, rather than the
+// token that follows an operand-less `yield`.
+static inline bool can_start_operand(int32_t c) {
+    return c != 0 && c != ';' && c != ',' && c != ')' && c != ']' && c != '}' && c != ':' && c != '?';
+}
+
+/**
+ * Skips whitespace and comments up to the next token, failing if they contain
+ * a line terminator. Used for the restricted productions `throw`, `yield` and `=>`.
+ */
+static bool skip_to_token_on_same_line(TSLexer *lexer, bool *scanned_comment) {
+    for (;;) {
+        if (is_line_terminator(lexer->lookahead)) {
+            return false;
+        }
+
+        if (iswspace(lexer->lookahead)) {
+            skip(lexer);
+            continue;
+        }
+
+        if (lexer->lookahead != '/') {
+            return true;
+        }
+
+        skip(lexer);
+        if (lexer->lookahead == '/') {
+            return false;
+        }
+        if (lexer->lookahead != '*') {
+            return true;
+        }
+
+        skip(lexer);
+        *scanned_comment = true;
+        for (;;) {
+            if (lexer->lookahead == 0 || is_line_terminator(lexer->lookahead)) {
+                return false;
+            }
+            if (lexer->lookahead == '*') {
+                skip(lexer);
+                if (lexer->lookahead == '/') {
+                    skip(lexer);
+                    break;
+                }
+            } else {
+                skip(lexer);
+            }
+        }
+    }
+}
+
+// The zero-width token before `=>`, only produced when the arrow follows on the
+// same line as the parameters.
+static bool scan_arrow_no_line_break(TSLexer *lexer) {
+    lexer->result_symbol = ARROW_NO_LINE_BREAK;
+    if (lexer->lookahead != '=') {
+        return false;
+    }
+    advance(lexer);
+    return lexer->lookahead == '>';
+}
+
+static bool scan_automatic_semicolon(TSLexer *lexer, bool comment_condition, bool arrow_condition,
+                                     bool operand_condition, bool *scanned_comment) {
     lexer->result_symbol = AUTOMATIC_SEMICOLON;
     lexer->mark_end(lexer);
 
@@ -140,6 +211,15 @@ static bool scan_automatic_semicolon(TSLexer *lexer, bool comment_condition, boo
         }
 
         if (!iswspace(lexer->lookahead)) {
+            // No line break was seen, so this may still be an arrow function or
+            // the operand of `yield`.
+            if (arrow_condition && scan_arrow_no_line_break(lexer)) {
+                return true;
+            }
+            if (operand_condition && can_start_operand(lexer->lookahead)) {
+                lexer->result_symbol = NO_LINE_BREAK;
+                return true;
+            }
             return false;
         }
 
@@ -148,27 +228,33 @@ static bool scan_automatic_semicolon(TSLexer *lexer, bool comment_condition, boo
 
     skip(lexer);
 
+    // A slash that does not start a comment begins a regex here.
     if (scan_whitespace_and_comments(lexer, scanned_comment, true) == REJECT) {
-        return false;
+        return comment_condition;
     }
 
     switch (lexer->lookahead) {
+        // These continue an expression from the previous line, but when the
+        // previous token cannot end one (`return`, `break`, a declarator name,
+        // ...), they start the next statement instead.
         case '`':
+        case '<':
+        case '[':
+        case '(':
+        case '/':
+            return comment_condition;
+
         case ',':
         case ':':
         case ';':
         case '*':
         case '%':
         case '>':
-        case '<':
         case '=':
-        case '[':
-        case '(':
         case '?':
         case '^':
         case '|':
         case '&':
-        case '/':
             return false;
 
         // Insert a semicolon before decimals literals but not otherwise.
@@ -179,10 +265,10 @@ static bool scan_automatic_semicolon(TSLexer *lexer, bool comment_condition, boo
         // Insert a semicolon before `--` and `++`, but not before binary `+` or `-`.
         case '+':
             skip(lexer);
-            return lexer->lookahead == '+';
+            return comment_condition || lexer->lookahead == '+';
         case '-':
             skip(lexer);
-            return lexer->lookahead == '-';
+            return comment_condition || lexer->lookahead == '-';
 
         // Don't insert a semicolon before `!=`, but do insert one before a unary `!`.
         case '!':
@@ -233,6 +319,11 @@ static bool scan_ternary_qmark(TSLexer *lexer) {
     if (lexer->lookahead == '?') {
         advance(lexer);
 
+        // `??` and `??=` are operators of their own.
+        if (lexer->lookahead == '?') {
+            return false;
+        }
+
         lexer->mark_end(lexer);
         lexer->result_symbol = TERNARY_QMARK;
 
@@ -285,6 +376,102 @@ static bool scan_html_comment(TSLexer *lexer) {
     return true;
 }
 
+static inline bool is_inline_space(int32_t c) { return c == ' ' || c == '\t'; }
+
+static bool scan_directive_attribute_name(TSLexer *lexer) {
+    static const char *names[] = {"types", "path", "lib", "no-default-lib", "resolution-mode", "preserve"};
+
+    char name[16];
+    unsigned length = 0;
+    while ((lexer->lookahead >= 'a' && lexer->lookahead <= 'z') || lexer->lookahead == '-') {
+        if (length == sizeof(name) - 1) {
+            return false;
+        }
+        name[length++] = (char)lexer->lookahead;
+        advance(lexer);
+    }
+    name[length] = '\0';
+
+    for (unsigned i = 0; i < sizeof(names) / sizeof(names[0]); i++) {
+        if (strcmp(name, names[i]) == 0) {
+            return true;
+        }
+    }
+    return false;
+}
+
+/**
+ * Scans `///` and `<reference` at the start of a reference directive. The
+ * token ends there, but the rest of the line is checked too: unless it holds
+ * known `name="value"` attributes and a closing `/>`, the line is left to be
+ * lexed as an ordinary comment.
+ */
+static bool scan_reference_directive_start(TSLexer *lexer) {
+    while (iswspace(lexer->lookahead)) {
+        skip(lexer);
+    }
+
+    for (unsigned i = 0; i < 3; i++) {
+        if (lexer->lookahead != '/') {
+            return false;
+        }
+        advance(lexer);
+    }
+    while (is_inline_space(lexer->lookahead)) {
+        advance(lexer);
+    }
+
+    const char *keyword = "<reference";
+    for (unsigned i = 0; keyword[i]; i++) {
+        if (lexer->lookahead != keyword[i]) {
+            return false;
+        }
+        advance(lexer);
+    }
+    lexer->result_symbol = REFERENCE_DIRECTIVE_START;
+    lexer->mark_end(lexer);
+
+    if (!is_inline_space(lexer->lookahead)) {
+        return false;
+    }
+    for (bool has_attribute = false;; has_attribute = true) {
+        while (is_inline_space(lexer->lookahead)) {
+            advance(lexer);
+        }
+        if (lexer->lookahead == '/') {
+            advance(lexer);
+            return has_attribute && lexer->lookahead == '>';
+        }
+
+        if (!scan_directive_attribute_name(lexer)) {
+            return false;
+        }
+        while (is_inline_space(lexer->lookahead)) {
+            advance(lexer);
+        }
+        if (lexer->lookahead != '=') {
+            return false;
+        }
+        advance(lexer);
+        while (is_inline_space(lexer->lookahead)) {
+            advance(lexer);
+        }
+
+        int32_t quote = lexer->lookahead;
+        if (quote != '"' && quote != '\'') {
+            return false;
+        }
+        advance(lexer);
+        while (lexer->lookahead != quote) {
+            if (lexer->lookahead == 0 || is_line_terminator(lexer->lookahead)) {
+                return false;
+            }
+            advance(lexer);
+        }
+        advance(lexer);
+    }
+}
+
 static bool scan_jsx_text(TSLexer *lexer) {
     // saw_text will be true if we see any non-whitespace content, or any whitespace content that is not a newline and
     // does not immediately follow a newline.
@@ -326,7 +513,7 @@ static bool scan_jsx_text(TSLexer *lexer) {
     return saw_text;
 }
 
-bool tree_sitter_javascript_external_scanner_scan(void *payload, TSLexer *lexer, const bool *valid_symbols) {
+bool tree_sitter_typescript_external_scanner_scan(void *payload, TSLexer *lexer, const bool *valid_symbols) {
     if (valid_symbols[TEMPLATE_CHARS]) {
         if (valid_symbols[AUTOMATIC_SEMICOLON]) {
             return false;
@@ -340,17 +527,54 @@ bool tree_sitter_javascript_external_scanner_scan(void *payload, TSLexer *lexer,
 
     if (valid_symbols[AUTOMATIC_SEMICOLON]) {
         bool scanned_comment = false;
-        bool ret = scan_automatic_semicolon(lexer, !valid_symbols[LOGICAL_OR], &scanned_comment);
+        // A line break after `yield` ends the expression, even before `(` or `[`.
+        bool restricted = valid_symbols[NO_LINE_BREAK];
+        bool ret = scan_automatic_semicolon(lexer, !valid_symbols[LOGICAL_OR] || restricted,
+                                            valid_symbols[ARROW_NO_LINE_BREAK], restricted, &scanned_comment);
         if (!ret && !scanned_comment && valid_symbols[TERNARY_QMARK] && lexer->lookahead == '?') {
             return scan_ternary_qmark(lexer);
         }
         return ret;
     }
 
+    // A `{` on the next line is the body of the function, not a new statement.
+    if (valid_symbols[FUNCTION_SIGNATURE_AUTOMATIC_SEMICOLON]) {
+        bool scanned_comment = false;
+        if (scan_automatic_semicolon(lexer, true, false, false, &scanned_comment) && lexer->lookahead != '{') {
+            lexer->result_symbol = FUNCTION_SIGNATURE_AUTOMATIC_SEMICOLON;
+            return true;
+        }
+        return false;
+    }
+
+    if (valid_symbols[ARROW_NO_LINE_BREAK]) {
+        lexer->mark_end(lexer);
+        bool scanned_comment = false;
+        if (skip_to_token_on_same_line(lexer, &scanned_comment) && scan_arrow_no_line_break(lexer)) {
+            return true;
+        }
+        if (scanned_comment || !valid_symbols[TERNARY_QMARK]) {
+            return false;
+        }
+    }
+
+    // A `?` cannot follow an operand-less `yield`, so the ternary scanner is
+    // not needed after it.
+    if (valid_symbols[NO_LINE_BREAK]) {
+        lexer->result_symbol = NO_LINE_BREAK;
+        lexer->mark_end(lexer);
+        bool scanned_comment = false;
+        return skip_to_token_on_same_line(lexer, &scanned_comment) && can_start_operand(lexer->lookahead);
+    }
+
     if (valid_symbols[TERNARY_QMARK]) {
         return scan_ternary_qmark(lexer);
     }
 
+    if (valid_symbols[REFERENCE_DIRECTIVE_START] && scan_reference_directive_start(lexer)) {
+        return true;
+    }
+
     if (valid_symbols[HTML_COMMENT] && !valid_symbols[LOGICAL_OR] && !valid_symbols[ESCAPE_SEQUENCE] &&
         !valid_symbols[REGEX_PATTERN]) {
         return scan_html_comment(lexer);
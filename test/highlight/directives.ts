@@ -0,0 +1,19 @@
+// @ts-nocheck
+// <- comment.directive
+
+/* @ts-check */
+// <- comment.directive
+
+// @ts-expect-error: the argument is deliberately wrong
+// <- comment.directive
+scale("2");
+
+// @ts-ignore
+// <- comment.directive
+scale(null);
+
+// Remember to drop the @ts-ignore above once scale accepts null.
+// <- comment
+
+// @ts-ignored is not a directive
+// <- comment
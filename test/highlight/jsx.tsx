@@ -0,0 +1,23 @@
+const icon = <svg:use xlink:href="#icon" title={title} />;
+//           ^ punctuation.bracket
+//            ^ tag
+//                    ^ attribute
+//                                ^ !attribute
+//                                       ^ attribute
+//                                              ^ !tag
+//                                                     ^ punctuation.bracket
+
+const page = <Layout.Main className="page">{children}</Layout.Main>;
+//            ^ tag
+//                   ^ tag
+//                        ^ attribute
+//                                          ^ !tag
+//                                                     ^ tag
+//                                                            ^ tag
+
+const list = <><Item /></>;
+//           ^ punctuation.bracket
+//            ^ punctuation.bracket
+//              ^ tag
+//                   ^ punctuation.bracket
+//                     ^ punctuation.bracket
@@ -0,0 +1,11 @@
+/**
+ * Scales every item by a factor.
+ * @param {number} factor
+ * @returns {number[]}
+ */
+function scale(factor) {
+  /* not documentation */
+  // neither is this
+  /**/
+  return items.map(item => item * factor);
+}
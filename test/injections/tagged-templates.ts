@@ -0,0 +1,28 @@
+const button = css`
+  color: ${color};
+  padding: 4px;
+`;
+
+const page = html`<main>${content}</main>`;
+
+const rows = sql`SELECT * FROM users WHERE id = ${id}`;
+
+const query = gql`
+  query User($id: ID!) {
+    user(id: $id) { name }
+  }
+`;
+
+const spin = keyframes`from { opacity: 0; } to { opacity: 1; }`;
+
+const Title = styled.h1`
+  font-size: ${size}px;
+`;
+
+const Primary = styled(Button)`background: blue;`;
+
+const Input = styled.input.attrs({ type: "text" })`border: none;`;
+
+// Not injected: unknown tags and untagged templates.
+const message = format`hello ${name}`;
+const plain = `color: red;`;